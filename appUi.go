@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,8 +10,15 @@ import (
 	"golang.org/x/term"
 	"os"
 	"strings"
+
+	"github.com/Oneiros-MUSH/dreamertgc/commands"
+	"github.com/Oneiros-MUSH/dreamertgc/theme"
 )
 
+// composerMaxLines bounds how tall the chat composer grows before it starts
+// scrolling internally instead of pushing the rest of the layout around.
+const composerMaxLines = 6
+
 type AppStage int
 
 const (
@@ -29,23 +37,45 @@ const (
 	NoError
 )
 
+// FocusState tracks which pane's viewport should receive scroll input
+// when the main screen (no modal window open) is active.
+type FocusState int
+
+const (
+	InputFocus FocusState = iota
+	MessagesFocus
+	SystemFocus
+)
+
 type AppMainModel struct {
 	stage         AppStage
 	errorState    ErrorState
+	focusState    FocusState
 	rcv           ServerEventReceiver
-	altWindow     AltWindow
+	windows       WindowManager
+	debugLog      []string
 	infoPane      CharacterPane
 	primaryPane   ChatPane
 	secondaryPane SystemPane
 	statusBar     StatusBar
-}
-
-type AltWindow struct {
-	IsFocused bool
-	Contents  []string
+	renderer      *chatRenderer
+	slashCommands *commands.Registry
+	editorTarget  EditorTarget
+	themes        *theme.Registry
+	activeTheme   theme.Theme
 }
 
 func (scr *AppMainModel) Init() tea.Cmd {
+	scr.rcv.MaxAttempts = ReconnectAttempts
+
+	scr.slashCommands = commands.NewRegistry()
+
+	scr.themes = theme.NewRegistry()
+	if err := scr.themes.LoadDir(theme.ThemesDir()); err != nil {
+		scr.debugLog = append(scr.debugLog, fmt.Sprintf("failed to load themes: %v", err))
+	}
+	scr.activeTheme = theme.Default()
+
 	return RunSignalRClient(&scr.rcv)
 }
 
@@ -53,141 +83,288 @@ func (scr *AppMainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case ServerConnectionEstablishedMsg:
-		scr.altWindow.Contents = append(scr.altWindow.Contents, "Connected!")
-		scr.altWindow.IsFocused = !scr.altWindow.IsFocused
+		if scr.windows.Has(connectWindowID) {
+			scr.windows.Pop()
+		}
+		scr.statusBar.MiddleString = ""
+		scr.debugLog = append(scr.debugLog, "Connected!")
 		tea.ExitAltScreen()
 		scr.primaryPane.IsFocused = true
-		scr.primaryPane.ChatInput.Blink()
 		scr.primaryPane.ChatInput.Focus()
-		return scr, scr.Listen(scr.rcv.UiUpdateChannel) // needs to kick off some sort of listener for incoming signalR invokes
+		return scr, tea.Batch(textarea.Blink, scr.Listen(scr.rcv.UiUpdateChannel)) // needs to kick off some sort of listener for incoming signalR invokes
 
 	case ServerDataReceivedMsg:
 		return scr, scr.Listen(scr.rcv.UiUpdateChannel)
 
+	case CloseWindowMsg:
+		return scr, scr.windows.Update(msg)
+
+	case editorFinishedMsg:
+		target := scr.editorTarget
+		scr.editorTarget = NoEditorTarget
+
+		content, err := readEditorResult(msg)
+		if err != nil {
+			scr.debugLog = append(scr.debugLog, fmt.Sprintf("$EDITOR handoff failed: %v", err))
+			return scr, nil
+		}
+
+		switch target {
+		case ChatInputEditorTarget:
+			scr.primaryPane.ChatInput.SetValue(content)
+			scr.primaryPane.ChatInput.Focus()
+		}
+		return scr, nil
+
+	case tea.WindowSizeMsg:
+		chatW, chatH := chatViewportSize(msg.Width, msg.Height)
+		scr.primaryPane.Viewport.Width = chatW
+		scr.primaryPane.Viewport.Height = chatH
+
+		if renderer, err := newChatRenderer(chatW); err == nil {
+			scr.renderer = renderer
+		}
+		scr.primaryPane.Viewport.SetContent(scr.renderChatContents())
+
+		sysW, sysH := systemViewportSize(msg.Width, msg.Height)
+		scr.secondaryPane.Viewport.Width = sysW
+		scr.secondaryPane.Viewport.Height = sysH
+		scr.secondaryPane.Viewport.SetContent(strings.Join(scr.secondaryPane.Contents, "\n"))
+		return scr, nil
+
 	case ErrMsg:
 		switch msg.ErrType {
 
 		case ServerConnectionTimeout:
 			scr.errorState = ServerConnectionTimeout
-			scr.altWindow.Contents = append(scr.altWindow.Contents, "Server Connection Failed. Retry? (Y/N)")
-			return scr, nil
+			return scr, scheduleReconnect(&scr.rcv)
+
+		case UnknownError:
+			cmd := scr.windows.Push(newErrorWindow("An unknown error occurred."))
+			return scr, cmd
 
 		case FatalError:
 			return scr, tea.Quit
 
 		}
 
+	case ConnectionStateChangedMsg:
+		scr.statusBar.MiddleString = connectionStatusLine(msg, scr.rcv.MaxAttempts)
+		scr.debugLog = append(scr.debugLog, scr.statusBar.MiddleString)
+
+		switch msg.State {
+		case Reconnecting:
+			if scr.windows.Has(connectWindowID) {
+				return scr, nil
+			}
+			cmd := scr.windows.Push(newConnectWindow(
+				scr.statusBar.MiddleString+" Retry now? (Y/N)",
+				func() tea.Cmd { scr.rcv.Epoch++; return RunSignalRClient(&scr.rcv) },
+				func() tea.Cmd { return cancelReconnect(&scr.rcv) },
+			))
+			return scr, cmd
+
+		case Failed:
+			if scr.windows.Has(connectWindowID) {
+				scr.windows.Pop()
+			}
+			cmd := scr.windows.Push(newErrorWindow(scr.statusBar.MiddleString))
+			return scr, cmd
+		}
+		return scr, nil
+
 	case tea.KeyMsg:
-		// altscreen keystroke handlers
-		if scr.altWindow.IsFocused {
-			if scr.errorState == ServerConnectionTimeout {
-				switch msg.Type {
-				case tea.KeyRunes:
-					switch string(msg.Runes) {
-					case "y":
-						scr.errorState = NoError
-						return scr, RunSignalRClient(&scr.rcv)
-					case "n":
-						return scr, tea.Quit
-					}
-				}
+		// route to the top-of-stack window first; it falls back to the main
+		// panes only once no window is open
+		if !scr.windows.Empty() {
+			cmd := scr.windows.Update(msg)
+			switch msg.Type {
+			case tea.KeyCtrlQ:
+				return scr, tea.Quit
+			case tea.KeyCtrlD:
+				scr.debugLog = append(scr.debugLog, fmt.Sprintf("chatFocused: %v \nchatContents: %v", scr.primaryPane.ChatInput.Focused(), scr.primaryPane.ChatInput.Value()))
 			}
+			return scr, cmd
 		}
 
 		// main screen keystroke handlers
-		if !scr.altWindow.IsFocused {
-			switch msg.Type {
+		switch msg.Type {
 
-			case tea.KeyEnter:
-				if !scr.primaryPane.ChatInput.Focused() {
-					scr.primaryPane.ChatInput.Focus()
-				}
-				if scr.primaryPane.ChatInput.Focused() && len(scr.primaryPane.ChatInput.Value()) > 0 {
-					scr.secondaryPane.Contents = append(scr.secondaryPane.Contents, fmt.Sprintf("Sent msg: %v\n", scr.primaryPane.ChatInput.Value()))
+		case tea.KeyTab:
+			if strings.HasPrefix(scr.primaryPane.ChatInput.Value(), "/") {
+				scr.suggestSlashCommands(scr.primaryPane.ChatInput.Value())
+				return scr, nil
+			}
+
+			// cycle focus between the chat input, the chat viewport, and the
+			// system viewport, so PgUp/PgDown/Home/End and the mouse wheel
+			// know which pane to scroll
+			switch scr.focusState {
+			case InputFocus:
+				scr.focusState = MessagesFocus
+				scr.primaryPane.ChatInput.Blur()
+			case MessagesFocus:
+				scr.focusState = SystemFocus
+			case SystemFocus:
+				scr.focusState = InputFocus
+				scr.primaryPane.ChatInput.Focus()
+			}
+			return scr, nil
+
+		case tea.KeyCtrlJ: // insert a newline into the composer instead of sending (classic key parsing can't see Shift+Enter)
+			var cmd tea.Cmd
+			scr.primaryPane.ChatInput, cmd = scr.primaryPane.ChatInput.Update(msg)
+			return scr, cmd
+
+		case tea.KeyCtrlE: // suspend and hand the composer off to $EDITOR
+			scr.editorTarget = ChatInputEditorTarget
+			return scr, openEditorCmd(ChatInputEditorTarget, scr.primaryPane.ChatInput.Value())
+
+		case tea.KeyEnter:
+			if !scr.primaryPane.ChatInput.Focused() {
+				scr.primaryPane.ChatInput.Focus()
+			}
+			if scr.primaryPane.ChatInput.Focused() && len(scr.primaryPane.ChatInput.Value()) > 0 {
+				value := scr.primaryPane.ChatInput.Value()
+				if strings.HasPrefix(value, "/") {
+					cmd := scr.runSlashCommand(value)
 					scr.primaryPane.ChatInput.Reset()
+					return scr, cmd
 				}
+
+				scr.secondaryPane.Contents = append(scr.secondaryPane.Contents, fmt.Sprintf("Sent msg: %v\n", value))
+				scr.primaryPane.ChatInput.Reset()
+				scr.primaryPane.Viewport.SetContent(scr.renderChatContents())
+				scr.primaryPane.Viewport.GotoBottom()
+				return scr, nil
+			}
+
+		case tea.KeyPgUp, tea.KeyPgDown, tea.KeyHome, tea.KeyEnd:
+			var cmd tea.Cmd
+			if scr.focusState == SystemFocus {
+				scr.secondaryPane.Viewport, cmd = scr.secondaryPane.Viewport.Update(msg)
+			} else {
+				scr.primaryPane.Viewport, cmd = scr.primaryPane.Viewport.Update(msg)
 			}
+			return scr, cmd
+		}
 
+		if scr.focusState == InputFocus {
 			scr.primaryPane.ChatInput, _ = scr.primaryPane.ChatInput.Update(msg)
 		}
 
 		// universal keystroke handlers
 		switch msg.Type {
-		case tea.KeyF6: // toggle debug screen
-			scr.altWindow.IsFocused = !scr.altWindow.IsFocused
+		case tea.KeyF6: // open the debug window
+			return scr, scr.windows.Push(newDebugWindow(&scr.debugLog))
 
-		case tea.KeyCtrlD: // dump current state to debug screen
-			scr.altWindow.Contents = append(scr.altWindow.Contents, fmt.Sprintf("chatFocused: %v \nchatContents: %v", scr.primaryPane.ChatInput.Focused(), scr.primaryPane.ChatInput.Value()))
+		case tea.KeyCtrlD: // dump current state to the debug log
+			scr.debugLog = append(scr.debugLog, fmt.Sprintf("chatFocused: %v \nchatContents: %v", scr.primaryPane.ChatInput.Focused(), scr.primaryPane.ChatInput.Value()))
 
 		case tea.KeyCtrlQ: // exit program
 			return scr, tea.Quit
 		}
+
+	case tea.MouseMsg:
+		if scr.windows.Empty() {
+			var cmd tea.Cmd
+			target := &scr.primaryPane.Viewport
+			if scr.focusState == SystemFocus {
+				target = &scr.secondaryPane.Viewport
+			}
+			switch msg.Type {
+			case tea.MouseWheelUp:
+				target.LineUp(3)
+			case tea.MouseWheelDown:
+				target.LineDown(3)
+			}
+			return scr, cmd
+		}
 	}
 	return scr, nil
 }
 
+// chatViewportSize mirrors the inner dimensions RenderChatPane uses for its
+// bordered box, minus the space taken by the chat input line.
+func chatViewportSize(w, h int) (int, int) {
+	return int((w/3)*2) - 3, int(((2*h)/3)-3) - 1
+}
+
+// systemViewportSize mirrors the inner dimensions RenderCommandPane uses for
+// its bordered box.
+func systemViewportSize(w, h int) (int, int) {
+	return int((w/3)*2) - 3, int((h/3) - 2)
+}
+
 func (scr *AppMainModel) View() string {
-	if scr.altWindow.IsFocused {
-		return RenderAltView(scr)
-	} else {
-		return RenderMainView(scr)
+	if !scr.windows.Empty() {
+		return RenderWindowView(scr)
 	}
+	return RenderMainView(scr)
 }
 
 func RenderMainView(scr *AppMainModel) string {
 	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
 
-	rightStack := lipgloss.JoinVertical(lipgloss.Right, scr.primaryPane.RenderChatPane(w, h), scr.secondaryPane.RenderCommandPane(w, h))
+	rightStack := lipgloss.JoinVertical(lipgloss.Right, scr.primaryPane.RenderChatPane(w, h, scr.activeTheme), scr.secondaryPane.RenderCommandPane(w, h, scr.activeTheme))
 	mainApp := lipgloss.JoinHorizontal(lipgloss.Top, scr.infoPane.RenderInfoPane(w, h), rightStack)
 
-	return mainApp + "\n" + scr.statusBar.RenderStatusBar(w)
+	return mainApp + "\n" + scr.statusBar.RenderStatusBar(w, scr.activeTheme)
 }
 
-func RenderAltView(scr *AppMainModel) string {
+// RenderWindowView renders the top-of-stack modal window in place of the
+// main panes, the same way the old AltWindow screen worked.
+func RenderWindowView(scr *AppMainModel) string {
 	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
 
-	mainStyle := lipgloss.NewStyle().
-		Width(w-2).Height(int(h-3)).Border(lipgloss.DoubleBorder(), true)
-
-	mainApp := mainStyle.Render(strings.Join(scr.altWindow.Contents, "\n"))
-
-	return mainApp + "\n" + scr.statusBar.RenderStatusBar(w)
+	return scr.windows.View(w, h, scr.activeTheme) + "\n" + scr.statusBar.RenderStatusBar(w, scr.activeTheme)
 }
 
 type ChatPane struct {
 	IsFocused bool
 	Contents  []string
-	ChatInput textinput.Model
+	ChatInput textarea.Model
+	Viewport  viewport.Model
 }
 
-func (pp *ChatPane) RenderChatPane(w int, h int) string {
+func (pp *ChatPane) RenderChatPane(w int, h int, th theme.Theme) string {
 	style := lipgloss.NewStyle().
-		Width(int((w/3)*2)-1).Height(int(((2*h)/3)-2)).Border(lipgloss.DoubleBorder(), true)
+		Width(int((w/3)*2)-1).Height(int(((2*h)/3)-2)).Border(lipgloss.DoubleBorder(), true).BorderForeground(th.BorderFg)
 
-	chatHistory := viewport.New(int((w/3)*2)-1, int(((2*h)/3)-3))
-	chatHistory.SetContent(strings.Join(pp.Contents, "\n"))
+	if pp.Viewport.Width == 0 {
+		vpW, vpH := chatViewportSize(w, h)
+		pp.Viewport = viewport.New(vpW, vpH)
+		pp.Viewport.SetContent(strings.Join(pp.Contents, "\n"))
+	}
 
-	pp.ChatInput.TextStyle = lipgloss.NewStyle().Background(lipgloss.Color("#AFAFAF")).Foreground(lipgloss.Color("#000000"))
-	//pp.ChatInput.BackgroundStyle = lipgloss.NewStyle().Background(lipgloss.Color("#AFAFAF")).Foreground(lipgloss.Color("#000000"))
-	//pp.ChatInput.PlaceholderStyle = lipgloss.NewStyle().Background(lipgloss.Color("#AFAFAF")).Foreground(lipgloss.Color("#000000"))
-	pp.ChatInput.Width = int((w/3)*2) - 5
-	pp.ChatInput.CharLimit = 255
-	//pp.ChatInput.Placeholder = "Chat Goes Here..."
+	pp.ChatInput.FocusedStyle.Text = lipgloss.NewStyle().Background(th.InputBg).Foreground(th.InputFg)
+	pp.ChatInput.BlurredStyle.Text = lipgloss.NewStyle().Background(th.InputBg).Foreground(th.InputFg)
+	pp.ChatInput.SetWidth(int((w/3)*2) - 5)
+	pp.ChatInput.CharLimit = 0
+	pp.ChatInput.MaxHeight = composerMaxLines
+	pp.ChatInput.ShowLineNumbers = false
 
-	return style.Render(chatHistory.View() + "\n" + pp.ChatInput.View())
+	return style.Render(pp.Viewport.View() + "\n" + pp.ChatInput.View())
 }
 
 type SystemPane struct {
 	IsFocused    bool
 	Contents     []string
 	commandInput textinput.Model
+	Viewport     viewport.Model
 }
 
-func (sp *SystemPane) RenderCommandPane(w int, h int) string {
+func (sp *SystemPane) RenderCommandPane(w int, h int, th theme.Theme) string {
 	style := lipgloss.NewStyle().
-		Width(int((w/3)*2)-1).Height(int((h/3)-2)).Border(lipgloss.DoubleBorder(), true)
+		Width(int((w/3)*2)-1).Height(int((h/3)-2)).Border(lipgloss.DoubleBorder(), true).BorderForeground(th.SystemAccent)
+
+	if sp.Viewport.Width == 0 {
+		vpW, vpH := systemViewportSize(w, h)
+		sp.Viewport = viewport.New(vpW, vpH)
+	}
+	sp.Viewport.SetContent(strings.Join(sp.Contents, "\n"))
 
-	return style.Render(strings.Join(sp.Contents, "\n"))
+	return style.Render(sp.Viewport.View())
 }
 
 type StatusBar struct {
@@ -196,14 +373,14 @@ type StatusBar struct {
 	RightBlurb   string
 }
 
-func (sb *StatusBar) RenderStatusBar(w int) string {
-	var statusBarLeftChunk = lipgloss.NewStyle().Background(lipgloss.Color("#FF5F87")).Foreground(lipgloss.Color("#FFFDF5")).Align(lipgloss.Left)
-	var statusBarRightChunk = lipgloss.NewStyle().Background(lipgloss.Color("#FF5F87")).Foreground(lipgloss.Color("#FFFDF5")).Align(lipgloss.Right)
+func (sb *StatusBar) RenderStatusBar(w int, th theme.Theme) string {
+	var statusBarLeftChunk = lipgloss.NewStyle().Background(th.StatusBarBg).Foreground(th.StatusBarFg).Align(lipgloss.Left)
+	var statusBarRightChunk = lipgloss.NewStyle().Background(th.StatusBarBg).Foreground(th.StatusBarFg).Align(lipgloss.Right)
 
 	left := statusBarLeftChunk.Render(sb.LeftBlurb)
 	right := statusBarRightChunk.Render(sb.RightBlurb)
 
-	var middleText = lipgloss.NewStyle().Background(lipgloss.Color("#AFAFAF")).Foreground(lipgloss.Color("#000000")).Align(lipgloss.Center).Width(w - lipgloss.Width(left) - lipgloss.Width(right))
+	var middleText = lipgloss.NewStyle().Background(th.InputBg).Foreground(th.InputFg).Align(lipgloss.Center).Width(w - lipgloss.Width(left) - lipgloss.Width(right))
 	middle := middleText.Render(sb.MiddleString)
 
 	statusBarPrerender := lipgloss.JoinHorizontal(lipgloss.Top, left, middle, right)
@@ -0,0 +1,163 @@
+// Package commands implements the "/"-prefixed slash-command palette for
+// the chat input line: parsing, a registry of built-ins, and completion
+// lookups for the Tab-triggered suggestion list.
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// App is the subset of AppMainModel a Command needs to act on. Handlers take
+// this interface rather than a concrete type so this package never imports
+// package main.
+type App interface {
+	Connect() tea.Cmd
+	Reconnect() tea.Cmd
+	Quit() tea.Cmd
+	Help() tea.Cmd
+	Debug() tea.Cmd
+	Focus(pane string) tea.Cmd
+	Theme(name string) tea.Cmd
+}
+
+// Command is a single slash command: a name, optional aliases, a usage
+// string shown on a bad-usage error, and the handler to run against the app.
+type Command struct {
+	Name    string
+	Aliases []string
+	Usage   string
+	Handler func(app App, args []string) (tea.Cmd, error)
+}
+
+// matches reports whether name (without the leading "/") is this command's
+// Name or one of its Aliases.
+func (c Command) matches(name string) bool {
+	if c.Name == name {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry holds the set of known slash commands.
+type Registry struct {
+	commands []Command
+}
+
+// NewRegistry returns a Registry pre-populated with the built-in commands.
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.Register(Command{
+		Name:  "connect",
+		Usage: "/connect - connect to the server",
+		Handler: func(app App, args []string) (tea.Cmd, error) {
+			return app.Connect(), nil
+		},
+	})
+	r.Register(Command{
+		Name:  "reconnect",
+		Usage: "/reconnect - reconnect to the server",
+		Handler: func(app App, args []string) (tea.Cmd, error) {
+			return app.Reconnect(), nil
+		},
+	})
+	r.Register(Command{
+		Name:    "quit",
+		Aliases: []string{"q"},
+		Usage:   "/quit - exit the program",
+		Handler: func(app App, args []string) (tea.Cmd, error) {
+			return app.Quit(), nil
+		},
+	})
+	r.Register(Command{
+		Name:  "help",
+		Usage: "/help - show the keybinding help window",
+		Handler: func(app App, args []string) (tea.Cmd, error) {
+			return app.Help(), nil
+		},
+	})
+	r.Register(Command{
+		Name:  "debug",
+		Usage: "/debug - show the debug window",
+		Handler: func(app App, args []string) (tea.Cmd, error) {
+			return app.Debug(), nil
+		},
+	})
+	focusUsage := "/focus <pane> - move focus to the named pane (input, messages, system)"
+	r.Register(Command{
+		Name:  "focus",
+		Usage: focusUsage,
+		Handler: func(app App, args []string) (tea.Cmd, error) {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("usage: %s", focusUsage)
+			}
+			return app.Focus(args[0]), nil
+		},
+	})
+	themeUsage := "/theme <name> - switch the active color theme"
+	r.Register(Command{
+		Name:  "theme",
+		Usage: themeUsage,
+		Handler: func(app App, args []string) (tea.Cmd, error) {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("usage: %s", themeUsage)
+			}
+			return app.Theme(args[0]), nil
+		},
+	})
+	return r
+}
+
+// Register adds c to the registry.
+func (r *Registry) Register(c Command) {
+	r.commands = append(r.commands, c)
+}
+
+// Lookup finds the command matching name (without the leading "/").
+func (r *Registry) Lookup(name string) (Command, bool) {
+	for _, c := range r.commands {
+		if c.matches(name) {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// Suggest returns the names of commands whose name starts with the given
+// "/"-prefixed, possibly partial input, for Tab completion.
+func (r *Registry) Suggest(input string) []string {
+	prefix := strings.TrimPrefix(input, "/")
+	var names []string
+	for _, c := range r.commands {
+		if strings.HasPrefix(c.Name, prefix) {
+			names = append(names, "/"+c.Name)
+		}
+	}
+	return names
+}
+
+// Execute parses a "/name arg1 arg2..." line and runs the matching command.
+// It returns an error describing unknown commands or bad usage rather than
+// returning it to the caller as a tea.Cmd, so callers can surface it however
+// they render system messages.
+func (r *Registry) Execute(app App, line string) (tea.Cmd, error) {
+	fields := strings.Fields(strings.TrimPrefix(line, "/"))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no command given")
+	}
+
+	name, args := fields[0], fields[1:]
+	cmd, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown command: /%s", name)
+	}
+
+	return cmd.Handler(app, args)
+}
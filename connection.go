@@ -0,0 +1,207 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ConnectionState models the lifecycle of the connection to the MU* server.
+type ConnectionState int
+
+const (
+	Disconnected ConnectionState = iota
+	Connecting
+	Connected
+	Reconnecting
+	Failed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	case Failed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ReconnectAttempts is the max-attempt ceiling for the reconnect backoff
+// loop. 0 means unlimited. Registered against the default FlagSet below, but
+// this tree has no main() yet to call flag.Parse() from, so until one
+// exists the registration is inert and ReconnectAttempts stays at its
+// default.
+var ReconnectAttempts = 10
+
+func init() {
+	flag.IntVar(&ReconnectAttempts, "reconnect-attempts", ReconnectAttempts, "max reconnect attempts before giving up (0 = unlimited)")
+}
+
+const (
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = 30 * time.Second
+	reconnectJitter    = 0.2 // +/-20%
+)
+
+// ServerEventReceiver owns the live connection state to the server and the
+// channel AppMainModel.Listen reads incoming SignalR invokes from.
+type ServerEventReceiver struct {
+	UiUpdateChannel chan tea.Msg
+	State           ConnectionState
+	Attempt         int
+	MaxAttempts     int
+
+	// Epoch is bumped every time a reconnect is scheduled or canceled, so a
+	// backoff tea.Tick scheduled for an earlier epoch can recognize it's
+	// stale (superseded or canceled) and no-op instead of firing anyway.
+	Epoch int
+}
+
+// ConnectionStateChangedMsg is emitted on every state transition so
+// AppMainModel can reflect it in the status bar and the debug window.
+type ConnectionStateChangedMsg struct {
+	State   ConnectionState
+	Attempt int
+	Wait    time.Duration
+}
+
+// ErrMsg reports a connection failure, tagged with the ErrorState the rest
+// of the UI already branches on.
+type ErrMsg struct {
+	ErrType ErrorState
+	Err     error
+}
+
+// ServerConnectionEstablishedMsg fires once RunSignalRClient completes a
+// successful handshake.
+type ServerConnectionEstablishedMsg struct{}
+
+// ServerDataReceivedMsg fires for each inbound payload read off the
+// connection's update channel.
+type ServerDataReceivedMsg struct{}
+
+// backoffDelay returns the exponential backoff delay for the given 1-indexed
+// attempt, capped at reconnectMaxDelay with +/-20% jitter.
+func backoffDelay(attempt int) time.Duration {
+	delay := float64(reconnectBaseDelay) * math.Pow(2, float64(attempt-1))
+	if delay > float64(reconnectMaxDelay) {
+		delay = float64(reconnectMaxDelay)
+	}
+	jitter := 1 + (rand.Float64()*2-1)*reconnectJitter
+	return time.Duration(delay * jitter)
+}
+
+// RunSignalRClient (re)connects rcv to the server. It's a no-op while a
+// connection attempt is already in flight or established.
+func RunSignalRClient(rcv *ServerEventReceiver) tea.Cmd {
+	if rcv.State == Connecting || rcv.State == Connected {
+		return nil
+	}
+	if rcv.UiUpdateChannel == nil {
+		rcv.UiUpdateChannel = make(chan tea.Msg)
+	}
+	rcv.State = Connecting
+
+	return func() tea.Msg {
+		// The actual SignalR handshake lives in the transport layer; this
+		// state machine only needs to react to its outcome.
+		if err := connectSignalRHub(rcv.UiUpdateChannel); err != nil {
+			return ErrMsg{ErrType: ServerConnectionTimeout, Err: err}
+		}
+		rcv.State = Connected
+		rcv.Attempt = 0
+		return ServerConnectionEstablishedMsg{}
+	}
+}
+
+// connectSignalRHub is the seam the real SignalR client plugs into; it's a
+// stand-in here since that transport code isn't part of this tree.
+func connectSignalRHub(uiUpdateChannel chan tea.Msg) error {
+	return nil
+}
+
+// Listen blocks for the next value pushed onto ch by the SignalR transport
+// and delivers it as a tea.Msg; AppMainModel.Update re-arms it after every
+// ServerConnectionEstablishedMsg/ServerDataReceivedMsg to keep draining it.
+func (scr *AppMainModel) Listen(ch chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// scheduleReconnect moves rcv into Reconnecting (or Failed, once the attempt
+// ceiling is hit) and returns the commands to announce the transition and,
+// if still retrying, wait out the backoff delay before trying again.
+func scheduleReconnect(rcv *ServerEventReceiver) tea.Cmd {
+	rcv.Attempt++
+
+	if rcv.MaxAttempts > 0 && rcv.Attempt > rcv.MaxAttempts {
+		rcv.State = Failed
+		return func() tea.Msg {
+			return ConnectionStateChangedMsg{State: Failed, Attempt: rcv.Attempt}
+		}
+	}
+
+	rcv.State = Reconnecting
+	rcv.Epoch++
+	epoch := rcv.Epoch
+	wait := backoffDelay(rcv.Attempt)
+
+	return tea.Batch(
+		func() tea.Msg {
+			return ConnectionStateChangedMsg{State: Reconnecting, Attempt: rcv.Attempt, Wait: wait}
+		},
+		tea.Tick(wait, func(time.Time) tea.Msg {
+			// A cancel or a newer scheduleReconnect call bumps rcv.Epoch;
+			// if that happened while this tick was waiting, it's stale.
+			if rcv.Epoch != epoch {
+				return nil
+			}
+			cmd := RunSignalRClient(rcv)
+			if cmd == nil {
+				return nil
+			}
+			return cmd()
+		}),
+	)
+}
+
+// cancelReconnect stops any future reconnect attempts by pinning the
+// ceiling to the attempts already made and bumping Epoch so the backoff
+// tea.Tick already in flight recognizes itself as stale and no-ops.
+func cancelReconnect(rcv *ServerEventReceiver) tea.Cmd {
+	rcv.MaxAttempts = rcv.Attempt
+	rcv.State = Failed
+	rcv.Epoch++
+	return func() tea.Msg {
+		return ConnectionStateChangedMsg{State: Failed, Attempt: rcv.Attempt}
+	}
+}
+
+// connectionStatusLine renders the middle status-bar / debug-log line for a
+// ConnectionStateChangedMsg, e.g. "Reconnecting in 4s (attempt 3/10)...".
+func connectionStatusLine(msg ConnectionStateChangedMsg, maxAttempts int) string {
+	switch msg.State {
+	case Reconnecting:
+		if maxAttempts > 0 {
+			return fmt.Sprintf("Reconnecting in %s (attempt %d/%d)...", msg.Wait.Round(time.Second), msg.Attempt, maxAttempts)
+		}
+		return fmt.Sprintf("Reconnecting in %s (attempt %d)...", msg.Wait.Round(time.Second), msg.Attempt)
+	case Failed:
+		return fmt.Sprintf("Connection failed after %d attempt(s)", msg.Attempt)
+	default:
+		return msg.State.String()
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EditorTarget names which field of the UI a suspended $EDITOR session is
+// writing back into once it exits.
+type EditorTarget int
+
+const (
+	NoEditorTarget EditorTarget = iota
+	ChatInputEditorTarget
+)
+
+// editorFinishedMsg reports that the suspended $EDITOR process returned,
+// carrying the temp file it edited so the caller can read it back.
+type editorFinishedMsg struct {
+	target EditorTarget
+	path   string
+	err    error
+}
+
+// openEditorCmd writes content to a temp file, then suspends the Bubble Tea
+// program and shells out to $EDITOR (falling back to vi) on it. The result
+// is delivered as an editorFinishedMsg once the editor exits.
+func openEditorCmd(target EditorTarget, content string) tea.Cmd {
+	file, err := os.CreateTemp("", "dreamertgc-*.txt")
+	if err != nil {
+		return func() tea.Msg { return editorFinishedMsg{target: target, err: err} }
+	}
+
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return func() tea.Msg { return editorFinishedMsg{target: target, err: err} }
+	}
+	path := file.Name()
+	file.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	c := exec.Command(editor, path)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorFinishedMsg{target: target, path: path, err: err}
+	})
+}
+
+// readEditorResult reads back the temp file from an editorFinishedMsg and
+// removes it. On any error the original content is preserved by the caller.
+func readEditorResult(msg editorFinishedMsg) (string, error) {
+	defer os.Remove(msg.path)
+	if msg.err != nil {
+		return "", msg.err
+	}
+
+	contents, err := os.ReadFile(msg.path)
+	if err != nil {
+		return "", err
+	}
+	return string(contents), nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+)
+
+// markdownCacheKey identifies a previously rendered chat line so View() can
+// avoid re-invoking glamour on every frame.
+type markdownCacheKey struct {
+	content string
+	width   int
+}
+
+// chatRenderer wraps a glamour.TermRenderer sized to the chat pane and caches
+// rendered output by (content, width) so repeated View() calls are cheap.
+type chatRenderer struct {
+	width int
+	term  *glamour.TermRenderer
+	cache map[markdownCacheKey]string
+}
+
+// newChatRenderer builds a renderer sized to the given chat pane width.
+func newChatRenderer(width int) (*chatRenderer, error) {
+	term, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatRenderer{
+		width: width,
+		term:  term,
+		cache: make(map[markdownCacheKey]string),
+	}, nil
+}
+
+// renderChatContents joins the chat pane's lines, rendering each through
+// scr.renderer when one has been sized (after the first WindowSizeMsg).
+// Lines that fail to render, or arrive before a renderer exists, pass through
+// unmodified so the chat history is never silently dropped.
+func (scr *AppMainModel) renderChatContents() string {
+	lines := make([]string, len(scr.primaryPane.Contents))
+	for i, line := range scr.primaryPane.Contents {
+		if scr.renderer == nil {
+			lines[i] = line
+			continue
+		}
+		lines[i] = scr.renderer.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Render returns content rendered through glamour, memoized on (content, width).
+func (cr *chatRenderer) Render(content string) string {
+	key := markdownCacheKey{content: content, width: cr.width}
+	if rendered, ok := cr.cache[key]; ok {
+		return rendered
+	}
+
+	rendered, err := cr.term.Render(content)
+	if err != nil {
+		return content
+	}
+
+	cr.cache[key] = rendered
+	return rendered
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Oneiros-MUSH/dreamertgc/commands"
+)
+
+// runSlashCommand parses and executes a "/"-prefixed chat input line,
+// surfacing unknown commands or bad usage as a SystemPane message instead of
+// sending them as chat.
+func (scr *AppMainModel) runSlashCommand(line string) tea.Cmd {
+	cmd, err := scr.slashCommands.Execute(scr, line)
+	if err != nil {
+		scr.secondaryPane.Contents = append(scr.secondaryPane.Contents, err.Error())
+		scr.secondaryPane.Viewport.SetContent(strings.Join(scr.secondaryPane.Contents, "\n"))
+		return nil
+	}
+	return cmd
+}
+
+// suggestSlashCommands prints the completions for a partial "/"-prefixed
+// input into the SystemPane, used by the Tab-triggered command palette.
+func (scr *AppMainModel) suggestSlashCommands(input string) {
+	suggestions := scr.slashCommands.Suggest(input)
+	if len(suggestions) == 0 {
+		return
+	}
+	scr.secondaryPane.Contents = append(scr.secondaryPane.Contents, strings.Join(suggestions, "  "))
+	scr.secondaryPane.Viewport.SetContent(strings.Join(scr.secondaryPane.Contents, "\n"))
+}
+
+// The methods below implement commands.App so AppMainModel can be handed
+// straight to the slash-command registry.
+
+func (scr *AppMainModel) Connect() tea.Cmd {
+	return RunSignalRClient(&scr.rcv)
+}
+
+func (scr *AppMainModel) Reconnect() tea.Cmd {
+	return RunSignalRClient(&scr.rcv)
+}
+
+func (scr *AppMainModel) Quit() tea.Cmd {
+	return tea.Quit
+}
+
+func (scr *AppMainModel) Help() tea.Cmd {
+	return scr.windows.Push(newHelpWindow())
+}
+
+func (scr *AppMainModel) Debug() tea.Cmd {
+	return scr.windows.Push(newDebugWindow(&scr.debugLog))
+}
+
+func (scr *AppMainModel) Focus(pane string) tea.Cmd {
+	switch pane {
+	case "input":
+		scr.focusState = InputFocus
+		scr.primaryPane.ChatInput.Focus()
+	case "messages":
+		scr.focusState = MessagesFocus
+		scr.primaryPane.ChatInput.Blur()
+	case "system":
+		scr.focusState = SystemFocus
+		scr.primaryPane.ChatInput.Blur()
+	default:
+		scr.secondaryPane.Contents = append(scr.secondaryPane.Contents, fmt.Sprintf("unknown pane: %s", pane))
+	}
+	return nil
+}
+
+func (scr *AppMainModel) Theme(name string) tea.Cmd {
+	t, ok := scr.themes.Lookup(name)
+	if !ok {
+		scr.secondaryPane.Contents = append(scr.secondaryPane.Contents, fmt.Sprintf("unknown theme: %s (available: %s)", name, strings.Join(scr.themes.Names(), ", ")))
+		return nil
+	}
+
+	scr.activeTheme = t
+	scr.secondaryPane.Contents = append(scr.secondaryPane.Contents, fmt.Sprintf("switched to theme: %s", t.Name))
+	return nil
+}
+
+var _ commands.App = (*AppMainModel)(nil)
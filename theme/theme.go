@@ -0,0 +1,179 @@
+// Package theme defines the lipgloss color palette used across the UI and
+// a Registry that resolves a theme by name, loading user-defined themes
+// from $XDG_CONFIG_HOME/dreamertgc/themes/ on top of the built-ins.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the full set of colors the UI pulls styles from, replacing what
+// used to be hardcoded lipgloss.Color calls in RenderStatusBar, RenderChatPane,
+// and the other render helpers.
+type Theme struct {
+	Name string
+
+	StatusBarBg lipgloss.Color
+	StatusBarFg lipgloss.Color
+
+	BorderFg lipgloss.Color
+
+	InputBg lipgloss.Color
+	InputFg lipgloss.Color
+
+	SystemAccent lipgloss.Color
+	ErrorAccent  lipgloss.Color
+}
+
+// Default is the original hardcoded pink theme, kept as the fallback.
+func Default() Theme {
+	return Theme{
+		Name:         "default",
+		StatusBarBg:  lipgloss.Color("#FF5F87"),
+		StatusBarFg:  lipgloss.Color("#FFFDF5"),
+		BorderFg:     lipgloss.Color("#FF5F87"),
+		InputBg:      lipgloss.Color("#AFAFAF"),
+		InputFg:      lipgloss.Color("#000000"),
+		SystemAccent: lipgloss.Color("#AFAFAF"),
+		ErrorAccent:  lipgloss.Color("#FF5F5F"),
+	}
+}
+
+// Dracula is the well-known Dracula palette (draculatheme.com).
+func Dracula() Theme {
+	return Theme{
+		Name:         "dracula",
+		StatusBarBg:  lipgloss.Color("#BD93F9"),
+		StatusBarFg:  lipgloss.Color("#282A36"),
+		BorderFg:     lipgloss.Color("#6272A4"),
+		InputBg:      lipgloss.Color("#44475A"),
+		InputFg:      lipgloss.Color("#F8F8F2"),
+		SystemAccent: lipgloss.Color("#8BE9FD"),
+		ErrorAccent:  lipgloss.Color("#FF5555"),
+	}
+}
+
+// SolarizedLight is Ethan Schoonover's Solarized Light palette.
+func SolarizedLight() Theme {
+	return Theme{
+		Name:         "solarized-light",
+		StatusBarBg:  lipgloss.Color("#268BD2"),
+		StatusBarFg:  lipgloss.Color("#FDF6E3"),
+		BorderFg:     lipgloss.Color("#93A1A1"),
+		InputBg:      lipgloss.Color("#EEE8D5"),
+		InputFg:      lipgloss.Color("#073642"),
+		SystemAccent: lipgloss.Color("#2AA198"),
+		ErrorAccent:  lipgloss.Color("#DC322F"),
+	}
+}
+
+// Registry resolves a Theme by name.
+type Registry struct {
+	themes map[string]Theme
+}
+
+// NewRegistry returns a Registry pre-populated with the built-in themes.
+func NewRegistry() *Registry {
+	r := &Registry{themes: make(map[string]Theme)}
+	r.Register(Default())
+	r.Register(Dracula())
+	r.Register(SolarizedLight())
+	return r
+}
+
+// Register adds or replaces a theme under its own Name.
+func (r *Registry) Register(t Theme) {
+	r.themes[t.Name] = t
+}
+
+// Lookup finds a theme by name.
+func (r *Registry) Lookup(name string) (Theme, bool) {
+	t, ok := r.themes[name]
+	return t, ok
+}
+
+// Names lists every registered theme name.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.themes))
+	for name := range r.themes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// themeFile mirrors a theme TOML file's shape before it's converted to a Theme.
+type themeFile struct {
+	Name         string `toml:"name"`
+	StatusBarBg  string `toml:"status_bar_bg"`
+	StatusBarFg  string `toml:"status_bar_fg"`
+	BorderFg     string `toml:"border_fg"`
+	InputBg      string `toml:"input_bg"`
+	InputFg      string `toml:"input_fg"`
+	SystemAccent string `toml:"system_accent"`
+	ErrorAccent  string `toml:"error_accent"`
+}
+
+func (tf themeFile) toTheme() Theme {
+	return Theme{
+		Name:         tf.Name,
+		StatusBarBg:  lipgloss.Color(tf.StatusBarBg),
+		StatusBarFg:  lipgloss.Color(tf.StatusBarFg),
+		BorderFg:     lipgloss.Color(tf.BorderFg),
+		InputBg:      lipgloss.Color(tf.InputBg),
+		InputFg:      lipgloss.Color(tf.InputFg),
+		SystemAccent: lipgloss.Color(tf.SystemAccent),
+		ErrorAccent:  lipgloss.Color(tf.ErrorAccent),
+	}
+}
+
+// ThemesDir returns $XDG_CONFIG_HOME/dreamertgc/themes, falling back to the
+// OS's default config directory when XDG_CONFIG_HOME isn't set.
+func ThemesDir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "dreamertgc", "themes")
+	}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "dreamertgc", "themes")
+	}
+	return ""
+}
+
+// LoadDir registers every *.toml theme file found in dir, overriding
+// built-ins of the same name. A missing directory is not an error.
+func (r *Registry) LoadDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".toml" {
+			continue
+		}
+
+		var tf themeFile
+		path := filepath.Join(dir, entry.Name())
+		if _, err := toml.DecodeFile(path, &tf); err != nil {
+			return fmt.Errorf("theme %s: %w", entry.Name(), err)
+		}
+		if tf.Name == "" {
+			tf.Name = strings.TrimSuffix(entry.Name(), ".toml")
+		}
+		r.Register(tf.toTheme())
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Oneiros-MUSH/dreamertgc/theme"
+)
+
+// WindowID names a concrete Window implementation for lookups within a
+// WindowManager's stack (e.g. "is the help window already open?").
+type WindowID string
+
+// Window is a modal dialog rendered on top of the main three-pane layout.
+// Concrete windows (errorWindow, debugWindow, helpWindow, connectWindow) implement this
+// so new modal features can be added without touching AppMainModel.Update.
+type Window interface {
+	Init() tea.Cmd
+	Update(msg tea.Msg) (Window, tea.Cmd)
+	View(w, h int, th theme.Theme) string
+	Focus()
+	Blur()
+	ID() WindowID
+}
+
+// CloseWindowMsg asks the WindowManager to pop the top-of-stack window. A
+// window's Update returns a tea.Cmd producing this message (e.g. on Esc)
+// rather than closing itself directly, since Window has no pop method.
+type CloseWindowMsg struct{}
+
+// CloseWindow is the tea.Cmd a Window returns to request that it be popped.
+func CloseWindow() tea.Msg {
+	return CloseWindowMsg{}
+}
+
+// WindowManager owns a stack of modal windows layered over the main panes.
+// Only the top of the stack is focused and receives messages.
+type WindowManager struct {
+	stack []Window
+}
+
+// Empty reports whether no window is currently open.
+func (wm *WindowManager) Empty() bool {
+	return len(wm.stack) == 0
+}
+
+// Top returns the focused, top-of-stack window, or nil if none is open.
+func (wm *WindowManager) Top() Window {
+	if wm.Empty() {
+		return nil
+	}
+	return wm.stack[len(wm.stack)-1]
+}
+
+// Has reports whether a window with the given ID is anywhere in the stack.
+func (wm *WindowManager) Has(id WindowID) bool {
+	for _, w := range wm.stack {
+		if w.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Push opens w on top of the stack, blurring the previous top (if any) and
+// focusing w, then returns w's Init command.
+func (wm *WindowManager) Push(w Window) tea.Cmd {
+	if top := wm.Top(); top != nil {
+		top.Blur()
+	}
+	wm.stack = append(wm.stack, w)
+	w.Focus()
+	return w.Init()
+}
+
+// Pop closes the top-of-stack window and focuses whatever is beneath it.
+func (wm *WindowManager) Pop() {
+	if wm.Empty() {
+		return
+	}
+	wm.stack = wm.stack[:len(wm.stack)-1]
+	if top := wm.Top(); top != nil {
+		top.Focus()
+	}
+}
+
+// Update routes msg to the top-of-stack window. CloseWindowMsg is handled
+// here rather than forwarded, since Window has no way to pop itself.
+func (wm *WindowManager) Update(msg tea.Msg) tea.Cmd {
+	top := wm.Top()
+	if top == nil {
+		return nil
+	}
+
+	if _, ok := msg.(CloseWindowMsg); ok {
+		wm.Pop()
+		return nil
+	}
+
+	updated, cmd := top.Update(msg)
+	wm.stack[len(wm.stack)-1] = updated
+	return cmd
+}
+
+// View renders the top-of-stack window, or "" if none is open.
+func (wm *WindowManager) View(w, h int, th theme.Theme) string {
+	top := wm.Top()
+	if top == nil {
+		return ""
+	}
+	return top.View(w, h, th)
+}
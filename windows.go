@@ -0,0 +1,158 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Oneiros-MUSH/dreamertgc/theme"
+)
+
+// windowStyle is the shared bordered box concrete windows render into,
+// matching the double-border look the old AltWindow used.
+func windowStyle(w, h int, th theme.Theme) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Width(w - 2).Height(h - 3).Border(lipgloss.DoubleBorder(), true).BorderForeground(th.BorderFg)
+}
+
+const (
+	errorWindowID   WindowID = "error"
+	debugWindowID   WindowID = "debug"
+	helpWindowID    WindowID = "help"
+	connectWindowID WindowID = "connect"
+)
+
+// errorWindow displays a single error message; Esc or Enter dismisses it.
+// This replaces AltWindow's role for UnknownError.
+type errorWindow struct {
+	Message string
+}
+
+func newErrorWindow(message string) *errorWindow {
+	return &errorWindow{Message: message}
+}
+
+func (w *errorWindow) Init() tea.Cmd { return nil }
+
+func (w *errorWindow) Update(msg tea.Msg) (Window, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.Type {
+		case tea.KeyEsc, tea.KeyEnter:
+			return w, CloseWindow
+		}
+	}
+	return w, nil
+}
+
+func (w *errorWindow) View(width, height int, th theme.Theme) string {
+	return windowStyle(width, height, th).BorderForeground(th.ErrorAccent).Render(w.Message)
+}
+
+func (w *errorWindow) Focus()       {}
+func (w *errorWindow) Blur()        {}
+func (w *errorWindow) ID() WindowID { return errorWindowID }
+
+// debugWindow shows the running debug/command log that Ctrl+D appends to.
+// It holds a pointer into AppMainModel's debugLog so entries appended while
+// the window is closed still show up the next time it's opened.
+type debugWindow struct {
+	log *[]string
+}
+
+func newDebugWindow(log *[]string) *debugWindow {
+	return &debugWindow{log: log}
+}
+
+func (w *debugWindow) Init() tea.Cmd { return nil }
+
+func (w *debugWindow) Update(msg tea.Msg) (Window, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.Type {
+		case tea.KeyEsc, tea.KeyF6:
+			return w, CloseWindow
+		}
+	}
+	return w, nil
+}
+
+func (w *debugWindow) View(width, height int, th theme.Theme) string {
+	return windowStyle(width, height, th).Render(strings.Join(*w.log, "\n"))
+}
+
+func (w *debugWindow) Focus()       {}
+func (w *debugWindow) Blur()        {}
+func (w *debugWindow) ID() WindowID { return debugWindowID }
+
+// helpWindow lists the program's keybindings. Nothing wires it to a key yet;
+// it's pushed by the slash-command palette.
+type helpWindow struct{}
+
+func newHelpWindow() *helpWindow {
+	return &helpWindow{}
+}
+
+var helpWindowLines = []string{
+	"Tab         cycle focus between chat input, chat history, and system log",
+	"PgUp/PgDn   scroll the focused history pane (chat or system)",
+	"Home/End    jump to top/bottom of the focused history pane",
+	"F6          toggle the debug window",
+	"Ctrl+D      dump state to the debug window",
+	"Ctrl+Q      quit",
+	"Esc         close this window",
+}
+
+func (w *helpWindow) Init() tea.Cmd { return nil }
+
+func (w *helpWindow) Update(msg tea.Msg) (Window, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEsc {
+		return w, CloseWindow
+	}
+	return w, nil
+}
+
+func (w *helpWindow) View(width, height int, th theme.Theme) string {
+	return windowStyle(width, height, th).Render(strings.Join(helpWindowLines, "\n"))
+}
+
+func (w *helpWindow) Focus()       {}
+func (w *helpWindow) Blur()        {}
+func (w *helpWindow) ID() WindowID { return helpWindowID }
+
+// connectWindow prompts to retry a failed or backed-off server connection.
+// It carries the reconnect/cancel commands so it doesn't need access to
+// AppMainModel: "y" aborts the backoff and reconnects immediately, "n"
+// cancels any future reconnect attempts. reconnect is a func() tea.Cmd
+// rather than a tea.Cmd so it isn't evaluated (and doesn't flip rcv's state)
+// until "y" is actually pressed.
+type connectWindow struct {
+	Message   string
+	reconnect func() tea.Cmd
+	cancel    func() tea.Cmd
+}
+
+func newConnectWindow(message string, reconnect func() tea.Cmd, cancel func() tea.Cmd) *connectWindow {
+	return &connectWindow{Message: message, reconnect: reconnect, cancel: cancel}
+}
+
+func (w *connectWindow) Init() tea.Cmd { return nil }
+
+func (w *connectWindow) Update(msg tea.Msg) (Window, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyRunes {
+		switch string(key.Runes) {
+		case "y":
+			return w, tea.Batch(w.reconnect(), CloseWindow)
+		case "n":
+			return w, tea.Batch(w.cancel(), CloseWindow)
+		}
+	}
+	return w, nil
+}
+
+func (w *connectWindow) View(width, height int, th theme.Theme) string {
+	return windowStyle(width, height, th).BorderForeground(th.ErrorAccent).Render(w.Message)
+}
+
+func (w *connectWindow) Focus()       {}
+func (w *connectWindow) Blur()        {}
+func (w *connectWindow) ID() WindowID { return connectWindowID }